@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// alertsCache avoids re-fetching active alerts on every request for the
+// same point within a short window; NWS alerts don't change second-to-second.
+var alertsCache = NewCache(envDuration("NWS_ALERTS_CACHE_TTL", 5*time.Minute))
+
+// AlertResponse is a trimmed view of one NWS alert feature, surfaced on the
+// /alerts endpoint and on /weather?includeAlerts=true.
+type AlertResponse struct {
+	Event       string `json:"event"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Instruction string `json:"instruction,omitempty"`
+}
+
+// severeSeverities lists the NWS severity values ("Extreme" and "Severe")
+// that should be promoted into the top-level forecast text.
+var severeSeverities = map[string]bool{
+	"Extreme": true,
+	"Severe":  true,
+}
+
+// nwsAlertFeatureCollection mirrors the GeoJSON FeatureCollection returned
+// by https://api.weather.gov/alerts/active.
+type nwsAlertFeatureCollection struct {
+	Features []struct {
+		Properties struct {
+			Event       string `json:"event"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			Instruction string `json:"instruction"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// fetchActiveAlerts calls NWS's active-alerts endpoint for lat/lon and
+// returns them as AlertResponse values.
+func fetchActiveAlerts(lat, lon float64) ([]AlertResponse, error) {
+	key := gridCacheKey(lat, lon)
+	if cached, ok := alertsCache.Get(key); ok {
+		return cached.([]AlertResponse), nil
+	}
+
+	url := fmt.Sprintf("https://api.weather.gov/alerts/active?point=%.4f,%.4f", lat, lon)
+	log.Printf("Calling NWS alerts API: %s", url)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active alerts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alerts API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerts response: %w", err)
+	}
+
+	var fc nwsAlertFeatureCollection
+	if err := json.Unmarshal(body, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts response: %w", err)
+	}
+
+	alerts := make([]AlertResponse, 0, len(fc.Features))
+	for _, f := range fc.Features {
+		alerts = append(alerts, AlertResponse{
+			Event:       f.Properties.Event,
+			Severity:    f.Properties.Severity,
+			Description: f.Properties.Description,
+			Instruction: f.Properties.Instruction,
+		})
+	}
+
+	alertsCache.Set(key, alerts)
+	return alerts, nil
+}
+
+// hasSevereAlert reports whether any alert in the slice is Severe or Extreme.
+func hasSevereAlert(alerts []AlertResponse) bool {
+	for _, a := range alerts {
+		if severeSeverities[a.Severity] {
+			return true
+		}
+	}
+	return false
+}
+
+// getAlerts handles GET /alerts?lat=&lon=.
+func getAlerts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	lat, lon, err := parseLatLon(r)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	alerts, err := fetchActiveAlerts(lat, lon)
+	if err != nil {
+		log.Printf("Error getting alerts: %v", err)
+		respondWithError(w, "Failed to retrieve alerts", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(struct {
+		Alerts []AlertResponse `json:"alerts"`
+	}{Alerts: alerts})
+}
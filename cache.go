@@ -0,0 +1,81 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheEntry holds a cached value alongside its expiry time.
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// Cache is a small in-memory TTL cache. It is safe for concurrent use.
+type Cache struct {
+	mu   sync.RWMutex
+	ttl  time.Duration
+	data map[string]cacheEntry
+
+	hits   uint64
+	misses uint64
+}
+
+// NewCache creates a Cache whose entries expire after ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:  ttl,
+		data: make(map[string]cacheEntry),
+	}
+}
+
+// Get returns the cached value for key, if present and not expired. An
+// expired entry is deleted on the way out so churning keys (e.g. many
+// distinct ?q= geocode lookups) don't grow the map without bound.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	entry, ok := c.data[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		c.mu.Lock()
+		if fresh, ok := c.data[key]; ok && time.Now().After(fresh.expires) {
+			delete(c.data, key)
+		}
+		c.misses++
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.hits++
+	c.mu.Unlock()
+	return entry.value, true
+}
+
+// Set stores value under key, using the cache's configured TTL.
+func (c *Cache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = cacheEntry{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+// Stats reports the number of cache hits, misses, and live entries.
+type Stats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	TTL     string `json:"ttl"`
+}
+
+// Stats returns a snapshot of the cache's hit/miss counters.
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Stats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.data),
+		TTL:     c.ttl.String(),
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var zipPattern = regexp.MustCompile(`^\d{5}$`)
+
+// countryAbbreviations expands common country abbreviations so they compare
+// equal to the full names Open-Meteo's geocoder returns.
+var countryAbbreviations = map[string]string{
+	"US":  "United States",
+	"USA": "United States",
+	"UK":  "United Kingdom",
+}
+
+// usStateAbbreviations expands two-letter US state/territory codes so a
+// query like "Austin, TX" matches Open-Meteo's full admin1 name ("Texas").
+var usStateAbbreviations = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas",
+	"CA": "California", "CO": "Colorado", "CT": "Connecticut", "DE": "Delaware",
+	"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho",
+	"IL": "Illinois", "IN": "Indiana", "IA": "Iowa", "KS": "Kansas",
+	"KY": "Kentucky", "LA": "Louisiana", "ME": "Maine", "MD": "Maryland",
+	"MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota", "MS": "Mississippi",
+	"MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+	"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+	"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma",
+	"OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina",
+	"SD": "South Dakota", "TN": "Tennessee", "TX": "Texas", "UT": "Utah",
+	"VT": "Vermont", "VA": "Virginia", "WA": "Washington", "WV": "West Virginia",
+	"WI": "Wisconsin", "WY": "Wyoming", "DC": "District of Columbia",
+}
+
+// geocodeCache memoizes query -> candidate lookups; place names don't move.
+var geocodeCache = NewCache(envDuration("GEOCODE_CACHE_TTL", 24*time.Hour))
+
+// geocodeResult is one candidate match for a place-name or ZIP lookup.
+type geocodeResult struct {
+	Name      string  `json:"name"`
+	Admin1    string  `json:"admin1"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ambiguousGeocodeError is returned when a query matches more than one
+// place and no administrative-area suffix narrowed it down.
+type ambiguousGeocodeError struct {
+	Query      string
+	Candidates []geocodeResult
+}
+
+func (e *ambiguousGeocodeError) Error() string {
+	return fmt.Sprintf("query %q is ambiguous: %d candidates", e.Query, len(e.Candidates))
+}
+
+// resolveQuery turns a ZIP code or free-form place name (optionally suffixed
+// with an administrative area, e.g. "Austin, TX") into a single lat/lon.
+func resolveQuery(query string) (lat, lon float64, err error) {
+	query = strings.TrimSpace(query)
+	if zipPattern.MatchString(query) {
+		return zipLookup(query)
+	}
+
+	name := query
+	admin := ""
+	if idx := strings.LastIndex(query, ","); idx != -1 {
+		name = strings.TrimSpace(query[:idx])
+		admin = strings.TrimSpace(query[idx+1:])
+	}
+	return geocodeLookup(name, admin)
+}
+
+// zipLookup resolves a 5-digit US ZIP code to a lat/lon via Zippopotam.us.
+// Open-Meteo's geocoder only indexes place names, not postal codes, so ZIPs
+// need a dedicated lookup rather than being passed through as a `name=`.
+func zipLookup(zip string) (lat, lon float64, err error) {
+	cacheKey := "zip:" + zip
+	if cached, ok := geocodeCache.Get(cacheKey); ok {
+		r := cached.(geocodeResult)
+		return r.Latitude, r.Longitude, nil
+	}
+
+	reqURL := fmt.Sprintf("https://api.zippopotam.us/us/%s", zip)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to reach ZIP lookup API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, 0, fmt.Errorf("no location found matching ZIP code %q", zip)
+	} else if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("ZIP lookup API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read ZIP lookup response: %w", err)
+	}
+
+	var parsed struct {
+		Places []struct {
+			Latitude  string `json:"latitude"`
+			Longitude string `json:"longitude"`
+		} `json:"places"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse ZIP lookup response: %w", err)
+	}
+	if len(parsed.Places) == 0 {
+		return 0, 0, fmt.Errorf("no location found matching ZIP code %q", zip)
+	}
+
+	place := parsed.Places[0]
+	lat, err = strconv.ParseFloat(place.Latitude, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse ZIP lookup latitude: %w", err)
+	}
+	lon, err = strconv.ParseFloat(place.Longitude, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse ZIP lookup longitude: %w", err)
+	}
+
+	geocodeCache.Set(cacheKey, geocodeResult{Latitude: lat, Longitude: lon})
+	return lat, lon, nil
+}
+
+// geocodeLookup resolves name (optionally filtered to the given admin area
+// or country abbreviation) to a single lat/lon pair via Open-Meteo.
+func geocodeLookup(name, admin string) (lat, lon float64, err error) {
+	cacheKey := name + "|" + admin
+	if cached, ok := geocodeCache.Get(cacheKey); ok {
+		r := cached.(geocodeResult)
+		return r.Latitude, r.Longitude, nil
+	}
+
+	results, err := fetchGeocodeCandidates(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(results) == 0 {
+		return 0, 0, fmt.Errorf("no location found matching %q", name)
+	}
+
+	if admin != "" {
+		expanded := admin
+		if full, ok := countryAbbreviations[strings.ToUpper(admin)]; ok {
+			expanded = full
+		} else if full, ok := usStateAbbreviations[strings.ToUpper(admin)]; ok {
+			expanded = full
+		}
+		filtered := results[:0]
+		for _, r := range results {
+			if strings.EqualFold(r.Admin1, expanded) || strings.EqualFold(r.Country, expanded) {
+				filtered = append(filtered, r)
+			}
+		}
+		results = filtered
+	}
+
+	if len(results) == 0 {
+		return 0, 0, fmt.Errorf("no location found matching %q with area %q", name, admin)
+	}
+	if len(results) > 1 {
+		return 0, 0, &ambiguousGeocodeError{Query: name, Candidates: results}
+	}
+
+	geocodeCache.Set(cacheKey, results[0])
+	return results[0].Latitude, results[0].Longitude, nil
+}
+
+// fetchGeocodeCandidates calls Open-Meteo's geocoding search for name.
+func fetchGeocodeCandidates(name string) ([]geocodeResult, error) {
+	reqURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s", url.QueryEscape(name))
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach geocoding API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoding response: %w", err)
+	}
+
+	var parsed struct {
+		Results []geocodeResult `json:"results"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse geocoding response: %w", err)
+	}
+	return parsed.Results, nil
+}
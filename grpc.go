@@ -0,0 +1,152 @@
+//go:build grpc
+
+package main
+
+// This file wires the gRPC service defined in weather.proto to the same
+// Forecast function the HTTP handlers use. The message types it references
+// (pb.ForecastRequest, pb.ForecastResponse, pb.Location, pb.Units, and the
+// pb.WeatherServiceServer interface) are generated from weather.proto via:
+//
+//	protoc --go_out=. --go-grpc_out=. weather.proto
+//
+// Generating them requires protoc and the protoc-gen-go/protoc-gen-go-grpc
+// plugins, which aren't available in every build environment, so the
+// generated pb package isn't checked in here; run the command above before
+// building with the "grpc" tag (go build -tags grpc ./...). The default
+// build excludes this file entirely (see grpc_stub.go) so the HTTP server
+// builds and runs without protoc or the gRPC dependencies installed.
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"weather-api/pb"
+)
+
+// grpcServer implements pb.WeatherServiceServer on top of Forecast.
+type grpcServer struct {
+	pb.UnimplementedWeatherServiceServer
+}
+
+func (s *grpcServer) Current(ctx context.Context, req *pb.ForecastRequest) (*pb.ForecastResponse, error) {
+	return s.forecast(ctx, req, false, 1)
+}
+
+func (s *grpcServer) Hourly(ctx context.Context, req *pb.ForecastRequest) (*pb.ForecastResponse, error) {
+	return s.forecast(ctx, req, true, 24)
+}
+
+func (s *grpcServer) FiveDay(ctx context.Context, req *pb.ForecastRequest) (*pb.ForecastResponse, error) {
+	return s.forecast(ctx, req, false, 10)
+}
+
+// forecast resolves req's location, calls Forecast, and maps both provider
+// and input errors onto gRPC status codes.
+func (s *grpcServer) forecast(ctx context.Context, req *pb.ForecastRequest, hourly bool, periods int) (*pb.ForecastResponse, error) {
+	lat, lon, err := resolveLocation(req.GetLocation())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	units := grpcUnitsToUnits(req.GetUnits())
+	result, err := Forecast(ctx, ForecastRequest{Lat: lat, Lon: lon, Units: units})
+	if err != nil {
+		return nil, mapForecastError(err)
+	}
+
+	resp := &pb.ForecastResponse{
+		ShortForecast: result.ShortForecast,
+		Temperature:   int32(convertFromFahrenheit(float64(result.TempF), units)),
+		Source:        result.Source,
+	}
+
+	if pp, ok := providerFor(result.Source).(PeriodsProvider); ok {
+		if rawPeriods, err := pp.Periods(lat, lon, hourly, periods); err == nil {
+			for _, p := range rawPeriods {
+				resp.Periods = append(resp.Periods, &pb.Period{
+					Name:             p.Name,
+					StartTime:        p.StartTime,
+					EndTime:          p.EndTime,
+					IsDaytime:        p.IsDaytime,
+					Temperature:      int32(p.Temperature),
+					TemperatureUnit:  p.TemperatureUnit,
+					ShortForecast:    p.ShortForecast,
+					DetailedForecast: p.DetailedForecast,
+				})
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// resolveLocation turns a pb.Location (coordinates, city, or ZIP) into lat/lon.
+func resolveLocation(loc *pb.Location) (lat, lon float64, err error) {
+	if loc == nil {
+		return 0, 0, fmt.Errorf("location is required")
+	}
+	if loc.GetCity() != "" {
+		return resolveQuery(loc.GetCity())
+	}
+	if loc.GetZip() != "" {
+		return resolveQuery(loc.GetZip())
+	}
+	return loc.GetLat(), loc.GetLon(), nil
+}
+
+func grpcUnitsToUnits(u pb.Units) Units {
+	switch u {
+	case pb.Units_METRIC:
+		return UnitsMetric
+	case pb.Units_STANDARD:
+		return UnitsStandard
+	default:
+		return UnitsImperial
+	}
+}
+
+// providerFor returns the WeatherProvider registered under name, if any.
+func providerFor(name string) WeatherProvider {
+	for _, p := range providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// mapForecastError maps a Forecast error to the gRPC status code that best
+// describes it: InvalidArgument for bad input coordinates, NotFound for
+// coverage gaps, Internal for everything else.
+func mapForecastError(err error) error {
+	msg := err.Error()
+	for _, sub := range []string{"latitude must be between -90 and 90", "longitude must be between -180 and 180"} {
+		if strings.Contains(msg, sub) {
+			return status.Error(codes.InvalidArgument, msg)
+		}
+	}
+	for _, sub := range []string{"outside NWS coverage area", "not found in NWS grid system", "no location found"} {
+		if strings.Contains(msg, sub) {
+			return status.Error(codes.NotFound, msg)
+		}
+	}
+	return status.Error(codes.Internal, msg)
+}
+
+// serveGRPC starts the gRPC server on addr, blocking until it stops.
+func serveGRPC(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := grpc.NewServer()
+	pb.RegisterWeatherServiceServer(srv, &grpcServer{})
+	return srv.Serve(lis)
+}
@@ -0,0 +1,12 @@
+//go:build !grpc
+
+package main
+
+import "fmt"
+
+// serveGRPC is a no-op stand-in used when the binary is built without the
+// "grpc" tag (the default). Build with -tags grpc, after generating the pb
+// package from weather.proto (see grpc.go), to get the real implementation.
+func serveGRPC(addr string) error {
+	return fmt.Errorf("gRPC support was not compiled in; rebuild with -tags grpc")
+}
@@ -3,9 +3,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -14,28 +14,26 @@ import (
 
 // WeatherResponse represents the response structure for our weather endpoint
 type WeatherResponse struct {
-	Forecast     string `json:"forecast"`
-	Temperature  string `json:"temperature"`
-	Coordinates  string `json:"coordinates"`
-	ErrorMessage string `json:"error,omitempty"`
-}
-
-// NWSResponse represents the National Weather Service API response structure
-type NWSResponse struct {
-	Properties struct {
-		Periods []struct {
-			ShortForecast   string `json:"shortForecast"`
-			Temperature     int    `json:"temperature"`
-			TemperatureUnit string `json:"temperatureUnit"`
-		} `json:"periods"`
-	} `json:"properties"`
+	Forecast         string           `json:"forecast"`
+	Temperature      string           `json:"temperature"`
+	TemperatureValue string           `json:"temperatureValue,omitempty"`
+	Coordinates      string           `json:"coordinates"`
+	Source           string           `json:"source,omitempty"`
+	FeelsLike        string           `json:"feelsLike,omitempty"`
+	Periods          []PeriodResponse `json:"periods,omitempty"`
+	Alerts           []AlertResponse  `json:"alerts,omitempty"`
+	ErrorMessage     string           `json:"error,omitempty"`
 }
 
 func main() {
 	r := mux.NewRouter()
 
-	// Weather endpoint
+	// Weather endpoints
 	r.HandleFunc("/weather", getWeather).Methods("GET")
+	r.HandleFunc("/weather/hourly", getWeatherHourly).Methods("GET")
+	r.HandleFunc("/weather/extended", getWeatherExtended).Methods("GET")
+	r.HandleFunc("/alerts", getAlerts).Methods("GET")
+	r.HandleFunc("/cache/stats", getCacheStats).Methods("GET")
 
 	// Health check endpoint
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -54,220 +52,195 @@ func main() {
 			<h1>Weather Service</h1>
 			<p>Use the /weather endpoint with latitude and longitude parameters:</p>
 			<p><code>/weather?lat=40.7128&lon=-74.0060</code></p>
-			
-			<h2>Example US Cities:</h2>
+			<p>Or a city name / US ZIP code: <code>/weather?q=Austin,TX</code></p>
+
+			<h2>Example Cities:</h2>
 			<p>Example: <a href="/weather?lat=40.7128&lon=-74.0060">New York City</a></p>
 			<p>Example: <a href="/weather?lat=34.0522&lon=-118.2437">Los Angeles</a></p>
 			<p>Example: <a href="/weather?lat=41.8781&lon=-87.6298">Chicago</a></p>
 			<p>Example: <a href="/weather?lat=25.7617&lon=-80.1918">Miami</a></p>
 			<p>Example: <a href="/weather?lat=47.6062&lon=-122.3321">Seattle</a></p>
-			
-			<h2>⚠️ Important Note:</h2>
-			<p><strong>This service only works for US locations.</strong> The National Weather Service API covers the United States and its territories only.</p>
-			<p>For international locations, coordinates outside the US will return an error.</p>
+			<p>Example (international): <a href="/weather?lat=48.8566&lon=2.3522">Paris</a></p>
+
+			<h2>Notes</h2>
+			<p>US coordinates are served by the National Weather Service; everywhere else falls back to Open-Meteo.</p>
+			<p>Add <code>?units=imperial|metric|standard</code> and <code>?includeAlerts=true</code> to customize the response.</p>
 		</body>
 		</html>
 		`
 		w.Write([]byte(html))
 	}).Methods("GET")
 
+	if addr := os.Getenv("GRPC_ADDR"); addr != "" {
+		go func() {
+			log.Printf("Starting gRPC weather service on %s", addr)
+			if err := serveGRPC(addr); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
+
 	log.Println("Starting weather service on :8080")
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
 
-func getWeather(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	// Parse query parameters
-	lat := r.URL.Query().Get("lat")
-	lon := r.URL.Query().Get("lon")
+// parseLatLon extracts and validates lat/lon from the request's query
+// parameters, resolving a `?q=` city/ZIP query first if lat/lon aren't set.
+func parseLatLon(r *http.Request) (lat, lon float64, err error) {
+	query := r.URL.Query()
+
+	if q := query.Get("q"); q != "" {
+		lat, lon, err = resolveQuery(q)
+		if err != nil {
+			if ambiguous, ok := err.(*ambiguousGeocodeError); ok {
+				return 0, 0, fmt.Errorf("%s; candidates: %v", ambiguous.Error(), ambiguous.Candidates)
+			}
+			return 0, 0, err
+		}
+		return lat, lon, nil
+	}
 
-	if lat == "" || lon == "" {
-		respondWithError(w, "Missing required parameters: lat and lon", http.StatusBadRequest)
-		return
+	latStr := query.Get("lat")
+	lonStr := query.Get("lon")
+	if latStr == "" || lonStr == "" {
+		return 0, 0, fmt.Errorf("missing required parameters: lat and lon (or q)")
 	}
 
-	// Validate coordinates
-	latFloat, err := strconv.ParseFloat(lat, 64)
+	lat, err = strconv.ParseFloat(latStr, 64)
 	if err != nil {
-		respondWithError(w, "Invalid latitude format", http.StatusBadRequest)
-		return
+		return 0, 0, fmt.Errorf("invalid latitude format")
 	}
-
-	lonFloat, err := strconv.ParseFloat(lon, 64)
+	lon, err = strconv.ParseFloat(lonStr, 64)
 	if err != nil {
-		respondWithError(w, "Invalid longitude format", http.StatusBadRequest)
-		return
+		return 0, 0, fmt.Errorf("invalid longitude format")
+	}
+
+	if lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude must be between -180 and 180")
 	}
+	return lat, lon, nil
+}
 
-	if latFloat < -90 || latFloat > 90 {
-		respondWithError(w, "Latitude must be between -90 and 90", http.StatusBadRequest)
+func getWeather(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	lat, lon, err := parseLatLon(r)
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if lonFloat < -180 || lonFloat > 180 {
-		respondWithError(w, "Longitude must be between -180 and 180", http.StatusBadRequest)
+	units, err := parseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		respondWithError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Get weather data from National Weather Service
-	forecast, temp, err := getNWSWeather(latFloat, lonFloat)
+	includeAlerts := r.URL.Query().Get("includeAlerts") == "true"
+
+	result, err := Forecast(r.Context(), ForecastRequest{Lat: lat, Lon: lon, Units: units, IncludeAlerts: includeAlerts})
 	if err != nil {
 		log.Printf("Error getting weather data: %v", err)
-
-		// Provide more specific error messages based on error type
-		if strings.Contains(err.Error(), "outside NWS coverage area") {
-			respondWithError(w, err.Error(), http.StatusBadRequest)
-		} else if strings.Contains(err.Error(), "not found in NWS grid system") {
-			respondWithError(w, err.Error(), http.StatusBadRequest)
-		} else {
-			respondWithError(w, "Failed to retrieve weather data", http.StatusInternalServerError)
-		}
+		respondWithError(w, "Failed to retrieve weather data", http.StatusInternalServerError)
 		return
 	}
 
-	// Determine temperature characterization
-	tempChar := characterizeTemperature(temp)
-
 	response := WeatherResponse{
-		Forecast:    forecast,
-		Temperature: tempChar,
-		Coordinates: fmt.Sprintf("%.4f, %.4f", latFloat, lonFloat),
+		Forecast:         result.ShortForecast,
+		Temperature:      characterizeTemperature(fahrenheitToCelsius(float64(result.TempF))),
+		TemperatureValue: formatTemp(float64(result.TempF), units),
+		Coordinates:      fmt.Sprintf("%.4f, %.4f", lat, lon),
+		Source:           result.Source,
+		FeelsLike:        formatTemp(result.FeelsLikeF, units),
+		Alerts:           result.Alerts,
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-// isNWSCoverageArea checks if coordinates are within the National Weather Service coverage area
-// NWS covers the United States and its territories
-func isNWSCoverageArea(lat, lon float64) bool {
-	// NWS coverage roughly covers:
-	// Continental US: 25°N to 50°N, 65°W to 125°W
-	// Alaska: 50°N to 75°N, 140°W to 180°E
-	// Hawaii: 19°N to 23°N, 154°W to 162°W
-	// Puerto Rico & Caribbean: 15°N to 20°N, 68°W to 80°W
-
-	// Main continental US (most restrictive bounds)
-	if lat >= 25 && lat <= 50 && lon >= -125 && lon <= -65 {
-		return true
-	}
-
-	// Alaska (roughly 50°N to 75°N, 140°W to 180°E)
-	if lat >= 50 && lat <= 75 && lon >= -180 && lon <= -140 {
-		return true
-	}
-
-	// Hawaii (roughly 19°N to 23°N, 154°W to 162°W)
-	if lat >= 19 && lat <= 23 && lon >= -162 && lon <= -154 {
-		return true
-	}
-
-	// Puerto Rico and Caribbean (roughly 15°N to 20°N, 68°W to 80°W)
-	if lat >= 15 && lat <= 20 && lon >= -80 && lon <= -68 {
-		return true
-	}
-
-	return false
+// getWeatherHourly handles GET /weather/hourly?lat=&lon=&periods=N. This
+// endpoint is served exclusively by NWS and has no Open-Meteo fallback, so
+// it only covers the US and its territories (see isNWSCoverageArea).
+func getWeatherHourly(w http.ResponseWriter, r *http.Request) {
+	getPeriods(w, r, true)
 }
 
-func getNWSWeather(lat, lon float64) (string, int, error) {
-	// Check if coordinates are within NWS coverage area
-	if !isNWSCoverageArea(lat, lon) {
-		return "", 0, fmt.Errorf("coordinates (%.4f, %.4f) are outside NWS coverage area (US and territories only)", lat, lon)
-	}
-
-	log.Printf("Fetching weather for coordinates: %.4f, %.4f", lat, lon)
-
-	// First, get the grid points for the coordinates
-	gridURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", lat, lon)
-	log.Printf("Calling NWS grid points API: %s", gridURL)
-
-	resp, err := http.Get(gridURL)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to get grid points: %w", err)
-	}
-	defer resp.Body.Close()
-
-	log.Printf("Grid points API response status: %d", resp.StatusCode)
-
-	if resp.StatusCode == http.StatusNotFound {
-		return "", 0, fmt.Errorf("coordinates (%.4f, %.4f) not found in NWS grid system - may be outside coverage area", lat, lon)
-	} else if resp.StatusCode != http.StatusOK {
-		return "", 0, fmt.Errorf("grid points API returned status: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to read grid points response: %w", err)
-	}
-
-	// Parse grid response to get forecast URL
-	var gridResp struct {
-		Properties struct {
-			Forecast string `json:"forecast"`
-		} `json:"properties"`
-	}
-
-	if err := json.Unmarshal(body, &gridResp); err != nil {
-		return "", 0, fmt.Errorf("failed to parse grid response: %w", err)
-	}
-
-	if gridResp.Properties.Forecast == "" {
-		return "", 0, fmt.Errorf("no forecast URL found in grid response")
-	}
+// getWeatherExtended handles GET /weather/extended?lat=&lon=&periods=N. This
+// endpoint is served exclusively by NWS and has no Open-Meteo fallback, so
+// it only covers the US and its territories (see isNWSCoverageArea).
+func getWeatherExtended(w http.ResponseWriter, r *http.Request) {
+	getPeriods(w, r, false)
+}
 
-	log.Printf("Forecast URL: %s", gridResp.Properties.Forecast)
+func getPeriods(w http.ResponseWriter, r *http.Request, hourly bool) {
+	w.Header().Set("Content-Type", "application/json")
 
-	// Get the actual forecast
-	forecastResp, err := http.Get(gridResp.Properties.Forecast)
+	lat, lon, err := parseLatLon(r)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to get forecast: %w", err)
+		respondWithError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	defer forecastResp.Body.Close()
-
-	log.Printf("Forecast API response status: %d", forecastResp.StatusCode)
 
-	if forecastResp.StatusCode != http.StatusOK {
-		return "", 0, fmt.Errorf("forecast API returned status: %d", forecastResp.StatusCode)
+	count := 0
+	if raw := r.URL.Query().Get("periods"); raw != "" {
+		count, err = strconv.Atoi(raw)
+		if err != nil || count < 0 {
+			respondWithError(w, "Invalid periods parameter", http.StatusBadRequest)
+			return
+		}
 	}
 
-	forecastBody, err := io.ReadAll(forecastResp.Body)
+	periods, err := NWSProvider{}.Periods(lat, lon, hourly, count)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to read forecast response: %w", err)
+		log.Printf("Error getting periods: %v", err)
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "outside NWS coverage area") {
+			statusCode = http.StatusBadRequest
+		}
+		respondWithError(w, err.Error(), statusCode)
+		return
 	}
 
-	var nwsResp NWSResponse
-	if err := json.Unmarshal(forecastBody, &nwsResp); err != nil {
-		return "", 0, fmt.Errorf("failed to parse forecast response: %w", err)
+	response := WeatherResponse{
+		Coordinates: fmt.Sprintf("%.4f, %.4f", lat, lon),
+		Source:      NWSProvider{}.Name(),
+		Periods:     periods,
 	}
-
-	if len(nwsResp.Properties.Periods) == 0 {
-		return "", 0, fmt.Errorf("no forecast periods found")
+	if len(periods) > 0 {
+		response.Forecast = periods[0].ShortForecast
 	}
 
-	// Get today's forecast (first period)
-	period := nwsResp.Properties.Periods[0]
-	log.Printf("Retrieved forecast: %s, Temperature: %d°%s", period.ShortForecast, period.Temperature, period.TemperatureUnit)
-
-	// Convert temperature to Fahrenheit if it's in Celsius
-	temperature := period.Temperature
-	if strings.ToUpper(period.TemperatureUnit) == "C" {
-		temperature = int(float64(period.Temperature)*9/5 + 32)
-		log.Printf("Converted temperature from %d°C to %d°F", period.Temperature, temperature)
-	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
 
-	return period.ShortForecast, temperature, nil
+// getCacheStats handles GET /cache/stats, a debug endpoint reporting
+// hit/miss counts for the grid, forecast, alerts, and geocode caches.
+func getCacheStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]Stats{
+		"grid":     gridCache.Stats(),
+		"forecast": fcstCache.Stats(),
+		"alerts":   alertsCache.Stats(),
+		"geocode":  geocodeCache.Stats(),
+	})
 }
 
-func characterizeTemperature(tempF int) string {
-	switch {
-	case tempF >= 80:
-		return "hot"
-	case tempF <= 40:
-		return "cold"
+// formatTemp renders a Fahrenheit value in the requested unit system.
+func formatTemp(tempF float64, units Units) string {
+	value := convertFromFahrenheit(tempF, units)
+	switch units {
+	case UnitsMetric:
+		return fmt.Sprintf("%.1f°C", value)
+	case UnitsStandard:
+		return fmt.Sprintf("%.1fK", value)
 	default:
-		return "moderate"
+		return fmt.Sprintf("%.0f°F", value)
 	}
 }
 
@@ -0,0 +1,68 @@
+package main
+
+// PeriodResponse is one forecast period returned by /weather/hourly and
+// /weather/extended, mirroring the richer period structure NWS exposes
+// (as opposed to the single-snapshot shortForecast/temperature pair that
+// the plain /weather endpoint returns).
+type PeriodResponse struct {
+	Name             string `json:"name,omitempty"`
+	StartTime        string `json:"startTime"`
+	EndTime          string `json:"endTime"`
+	IsDaytime        bool   `json:"isDaytime"`
+	Temperature      int    `json:"temperature"`
+	TemperatureUnit  string `json:"temperatureUnit"`
+	TemperatureTrend string `json:"temperatureTrend,omitempty"`
+	WindSpeed        string `json:"windSpeed,omitempty"`
+	WindDirection    string `json:"windDirection,omitempty"`
+	ShortForecast    string `json:"shortForecast"`
+	DetailedForecast string `json:"detailedForecast,omitempty"`
+}
+
+// PeriodsProvider is implemented by providers that can return more than a
+// single forecast snapshot. NWSProvider implements it; OpenMeteoProvider
+// currently does not, since the fallback path only needs "right now".
+type PeriodsProvider interface {
+	Periods(lat, lon float64, hourly bool, count int) ([]PeriodResponse, error)
+}
+
+// Periods returns up to count forecast periods for lat/lon, pulling from
+// either the daily "forecast" URL or the "forecastHourly" URL depending on
+// hourly.
+func (p NWSProvider) Periods(lat, lon float64, hourly bool, count int) ([]PeriodResponse, error) {
+	point, err := p.resolvePoint(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	url := point.Forecast
+	if hourly {
+		url = point.ForecastHourly
+	}
+
+	raw, err := p.fetchPeriods(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 || count > len(raw) {
+		count = len(raw)
+	}
+
+	periods := make([]PeriodResponse, 0, count)
+	for _, period := range raw[:count] {
+		periods = append(periods, PeriodResponse{
+			Name:             period.Name,
+			StartTime:        period.StartTime,
+			EndTime:          period.EndTime,
+			IsDaytime:        period.IsDaytime,
+			Temperature:      period.Temperature,
+			TemperatureUnit:  period.TemperatureUnit,
+			TemperatureTrend: period.TemperatureTrend,
+			WindSpeed:        period.WindSpeed,
+			WindDirection:    period.WindDirection,
+			ShortForecast:    period.ShortForecast,
+			DetailedForecast: period.DetailedForecast,
+		})
+	}
+	return periods, nil
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WeatherProvider is implemented by anything that can turn a coordinate pair
+// into a short forecast summary and a Fahrenheit temperature. This lets
+// getWeather (and service.Forecast) fall back from one data source to
+// another without caring how each one talks to its upstream API.
+type WeatherProvider interface {
+	// Name identifies the provider for the response's "source" field.
+	Name() string
+	// Forecast returns today's short forecast and temperature (°F) for lat/lon.
+	Forecast(lat, lon float64) (shortForecast string, tempF int, err error)
+}
+
+var (
+	gridCache = NewCache(envDuration("NWS_GRID_CACHE_TTL", 24*time.Hour))
+	fcstCache = NewCache(envDuration("NWS_FORECAST_CACHE_TTL", 10*time.Minute))
+)
+
+// envDuration reads a duration from the named environment variable, falling
+// back to def when unset or invalid.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid duration for %s=%q, using default %s", name, v, def)
+		return def
+	}
+	return d
+}
+
+// gridCacheKey rounds coordinates to ~1km precision so that nearby requests
+// share the same cached NWS grid lookup.
+func gridCacheKey(lat, lon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", lat, lon)
+}
+
+// nwsPoint is the subset of the /points/{lat},{lon} response we need.
+type nwsPoint struct {
+	GridID         string
+	GridX, GridY   int
+	Forecast       string
+	ForecastHourly string
+}
+
+// NWSProvider serves forecasts from the National Weather Service API. It
+// only covers the US and its territories; see isNWSCoverageArea.
+type NWSProvider struct{}
+
+func (NWSProvider) Name() string { return "nws" }
+
+func (p NWSProvider) Forecast(lat, lon float64) (string, int, error) {
+	point, err := p.resolvePoint(lat, lon)
+	if err != nil {
+		return "", 0, err
+	}
+
+	periods, err := p.fetchPeriods(point.Forecast)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(periods) == 0 {
+		return "", 0, fmt.Errorf("no forecast periods found")
+	}
+
+	period := periods[0]
+	log.Printf("Retrieved forecast: %s, Temperature: %d°%s", period.ShortForecast, period.Temperature, period.TemperatureUnit)
+
+	temperature := period.Temperature
+	if strings.ToUpper(period.TemperatureUnit) == "C" {
+		temperature = int(float64(period.Temperature)*9/5 + 32)
+		log.Printf("Converted temperature from %d°C to %d°F", period.Temperature, temperature)
+	}
+
+	return period.ShortForecast, temperature, nil
+}
+
+// resolvePoint looks up the NWS grid cell for lat/lon, using gridCache to
+// avoid repeat /points calls for nearby coordinates.
+func (p NWSProvider) resolvePoint(lat, lon float64) (nwsPoint, error) {
+	if !isNWSCoverageArea(lat, lon) {
+		return nwsPoint{}, fmt.Errorf("coordinates (%.4f, %.4f) are outside NWS coverage area (US and territories only)", lat, lon)
+	}
+
+	key := gridCacheKey(lat, lon)
+	if cached, ok := gridCache.Get(key); ok {
+		return cached.(nwsPoint), nil
+	}
+
+	gridURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", lat, lon)
+	log.Printf("Calling NWS grid points API: %s", gridURL)
+
+	resp, err := http.Get(gridURL)
+	if err != nil {
+		return nwsPoint{}, fmt.Errorf("failed to get grid points: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nwsPoint{}, fmt.Errorf("coordinates (%.4f, %.4f) not found in NWS grid system - may be outside coverage area", lat, lon)
+	} else if resp.StatusCode != http.StatusOK {
+		return nwsPoint{}, fmt.Errorf("grid points API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nwsPoint{}, fmt.Errorf("failed to read grid points response: %w", err)
+	}
+
+	var gridResp struct {
+		Properties struct {
+			GridID         string `json:"gridId"`
+			GridX          int    `json:"gridX"`
+			GridY          int    `json:"gridY"`
+			Forecast       string `json:"forecast"`
+			ForecastHourly string `json:"forecastHourly"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &gridResp); err != nil {
+		return nwsPoint{}, fmt.Errorf("failed to parse grid response: %w", err)
+	}
+	if gridResp.Properties.Forecast == "" {
+		return nwsPoint{}, fmt.Errorf("no forecast URL found in grid response")
+	}
+
+	point := nwsPoint{
+		GridID:         gridResp.Properties.GridID,
+		GridX:          gridResp.Properties.GridX,
+		GridY:          gridResp.Properties.GridY,
+		Forecast:       gridResp.Properties.Forecast,
+		ForecastHourly: gridResp.Properties.ForecastHourly,
+	}
+	gridCache.Set(key, point)
+	return point, nil
+}
+
+// nwsForecastPeriod mirrors one entry of the NWS forecast "periods" array.
+type nwsForecastPeriod struct {
+	Name             string `json:"name"`
+	StartTime        string `json:"startTime"`
+	EndTime          string `json:"endTime"`
+	IsDaytime        bool   `json:"isDaytime"`
+	Temperature      int    `json:"temperature"`
+	TemperatureUnit  string `json:"temperatureUnit"`
+	TemperatureTrend string `json:"temperatureTrend"`
+	WindSpeed        string `json:"windSpeed"`
+	WindDirection    string `json:"windDirection"`
+	ShortForecast    string `json:"shortForecast"`
+	DetailedForecast string `json:"detailedForecast"`
+	RelativeHumidity struct {
+		Value float64 `json:"value"`
+	} `json:"relativeHumidity"`
+}
+
+// fetchPeriods retrieves and caches the forecast periods served at url
+// (either the daily "forecast" or "forecastHourly" NWS endpoint).
+func (p NWSProvider) fetchPeriods(url string) ([]nwsForecastPeriod, error) {
+	if cached, ok := fcstCache.Get(url); ok {
+		return cached.([]nwsForecastPeriod), nil
+	}
+
+	log.Printf("Calling NWS forecast API: %s", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("forecast API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forecast response: %w", err)
+	}
+
+	var nwsResp struct {
+		Properties struct {
+			Periods []nwsForecastPeriod `json:"periods"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &nwsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse forecast response: %w", err)
+	}
+
+	fcstCache.Set(url, nwsResp.Properties.Periods)
+	return nwsResp.Properties.Periods, nil
+}
+
+// windSpeedPattern extracts the leading mph figure from NWS wind strings
+// like "10 mph" or "15 to 20 mph" (the lower bound is used).
+var windSpeedPattern = regexp.MustCompile(`(\d+(\.\d+)?)`)
+
+// ApparentConditions returns the wind speed (mph) and relative humidity (%)
+// from today's forecast period, for use in feels-like calculations.
+func (p NWSProvider) ApparentConditions(lat, lon float64) (windMph, humidity float64, err error) {
+	point, err := p.resolvePoint(lat, lon)
+	if err != nil {
+		return 0, 0, err
+	}
+	periods, err := p.fetchPeriods(point.Forecast)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(periods) == 0 {
+		return 0, 0, fmt.Errorf("no forecast periods found")
+	}
+
+	period := periods[0]
+	if m := windSpeedPattern.FindString(period.WindSpeed); m != "" {
+		windMph, _ = strconv.ParseFloat(m, 64)
+	}
+	return windMph, period.RelativeHumidity.Value, nil
+}
+
+// isNWSCoverageArea checks if coordinates are within the National Weather Service coverage area
+// NWS covers the United States and its territories
+func isNWSCoverageArea(lat, lon float64) bool {
+	// NWS coverage roughly covers:
+	// Continental US: 25°N to 50°N, 65°W to 125°W
+	// Alaska: 50°N to 75°N, 140°W to 180°E
+	// Hawaii: 19°N to 23°N, 154°W to 162°W
+	// Puerto Rico & Caribbean: 15°N to 20°N, 68°W to 80°W
+
+	// Main continental US (most restrictive bounds)
+	if lat >= 25 && lat <= 50 && lon >= -125 && lon <= -65 {
+		return true
+	}
+
+	// Alaska (roughly 50°N to 75°N, 140°W to 180°E)
+	if lat >= 50 && lat <= 75 && lon >= -180 && lon <= -140 {
+		return true
+	}
+
+	// Hawaii (roughly 19°N to 23°N, 154°W to 162°W)
+	if lat >= 19 && lat <= 23 && lon >= -162 && lon <= -154 {
+		return true
+	}
+
+	// Puerto Rico and Caribbean (roughly 15°N to 20°N, 68°W to 80°W)
+	if lat >= 15 && lat <= 20 && lon >= -80 && lon <= -68 {
+		return true
+	}
+
+	return false
+}
+
+// OpenMeteoProvider serves forecasts from the Open-Meteo API, which has
+// worldwide coverage and needs no API key. It's used as a fallback whenever
+// NWSProvider can't serve a request.
+type OpenMeteoProvider struct{}
+
+func (OpenMeteoProvider) Name() string { return "open-meteo" }
+
+func (OpenMeteoProvider) Forecast(lat, lon float64) (string, int, error) {
+	url := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&current=temperature_2m,weather_code&hourly=temperature_2m,precipitation_probability&daily=sunrise,sunset&timezone=auto",
+		lat, lon,
+	)
+	log.Printf("Calling Open-Meteo API: %s", url)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get open-meteo forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("open-meteo API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read open-meteo response: %w", err)
+	}
+
+	var omResp struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			WeatherCode int     `json:"weather_code"`
+		} `json:"current"`
+	}
+	if err := json.Unmarshal(body, &omResp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse open-meteo response: %w", err)
+	}
+
+	tempF := int(omResp.Current.Temperature*9/5 + 32)
+	return weatherCodeToForecast(omResp.Current.WeatherCode), tempF, nil
+}
+
+// weatherCodeToForecast maps a WMO weather code, as returned by Open-Meteo,
+// to a short human-readable forecast string.
+func weatherCodeToForecast(code int) string {
+	switch {
+	case code == 0:
+		return "Clear"
+	case code >= 1 && code <= 3:
+		return "Partly Cloudy"
+	case code == 45 || code == 48:
+		return "Fog"
+	case code >= 51 && code <= 67:
+		return "Rain"
+	case code >= 71 && code <= 77:
+		return "Snow"
+	case code >= 80 && code <= 82:
+		return "Showers"
+	case code >= 95 && code <= 99:
+		return "Thunderstorm"
+	default:
+		return "Unknown"
+	}
+}
+
+// getNWSWeather is kept for backwards compatibility with callers that only
+// need the simple NWS path; new code should go through service.Forecast.
+func getNWSWeather(lat, lon float64) (string, int, error) {
+	return NWSProvider{}.Forecast(lat, lon)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// ForecastRequest describes a weather lookup in provider/transport-neutral
+// terms, so the same logic can be driven by the HTTP handlers and the gRPC
+// service.
+type ForecastRequest struct {
+	Lat, Lon      float64
+	Units         Units
+	IncludeAlerts bool
+}
+
+// ForecastResult is what Forecast returns: a snapshot forecast plus whatever
+// the caller asked for (alerts, apparent temperature).
+type ForecastResult struct {
+	ShortForecast string
+	TempF         int
+	Source        string
+	FeelsLikeF    float64
+	Alerts        []AlertResponse
+}
+
+// providers are tried in order; the first one that can serve lat/lon wins.
+// NWS is more detailed so it's preferred when it covers the coordinates.
+var providers = []WeatherProvider{NWSProvider{}, OpenMeteoProvider{}}
+
+// Forecast resolves a single current-conditions forecast for req, trying
+// each provider in turn and falling back to the next on coverage or upstream
+// errors. It is the shared entry point for both the HTTP handlers and the
+// gRPC service - neither talks to a provider directly.
+func Forecast(ctx context.Context, req ForecastRequest) (*ForecastResult, error) {
+	if req.Lat < -90 || req.Lat > 90 {
+		return nil, fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if req.Lon < -180 || req.Lon > 180 {
+		return nil, fmt.Errorf("longitude must be between -180 and 180")
+	}
+
+	var lastErr error
+	for _, p := range providers {
+		shortForecast, tempF, err := p.Forecast(req.Lat, req.Lon)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		result := &ForecastResult{
+			ShortForecast: shortForecast,
+			TempF:         tempF,
+			Source:        p.Name(),
+			FeelsLikeF:    float64(tempF),
+		}
+
+		// feelsLike needs wind/humidity, which only NWSProvider exposes today;
+		// OpenMeteoProvider falls back to the raw reading.
+		if apparent, ok := p.(interface {
+			ApparentConditions(lat, lon float64) (windMph, humidity float64, err error)
+		}); ok {
+			if windMph, humidity, err := apparent.ApparentConditions(req.Lat, req.Lon); err == nil {
+				result.FeelsLikeF = feelsLikeF(float64(tempF), windMph, humidity)
+			}
+		}
+
+		if req.IncludeAlerts {
+			alerts, err := fetchActiveAlerts(req.Lat, req.Lon)
+			if err != nil {
+				// Alerts are best-effort: a failure here shouldn't sink an
+				// otherwise-successful forecast.
+				alerts = nil
+			}
+			result.Alerts = alerts
+			if hasSevereAlert(alerts) {
+				result.ShortForecast = fmt.Sprintf("%s (severe weather alert in effect)", result.ShortForecast)
+			}
+		}
+
+		return result, nil
+	}
+
+	return nil, lastErr
+}
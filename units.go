@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Units identifies which temperature system a response should be rendered in.
+type Units string
+
+const (
+	UnitsImperial Units = "imperial" // Fahrenheit
+	UnitsMetric   Units = "metric"   // Celsius
+	UnitsStandard Units = "standard" // Kelvin
+)
+
+// parseUnits validates the `?units=` query parameter, defaulting to imperial.
+func parseUnits(raw string) (Units, error) {
+	switch Units(raw) {
+	case "":
+		return UnitsImperial, nil
+	case UnitsImperial, UnitsMetric, UnitsStandard:
+		return Units(raw), nil
+	default:
+		return "", fmt.Errorf("invalid units %q: must be imperial, metric, or standard", raw)
+	}
+}
+
+func fahrenheitToCelsius(f float64) float64 { return (f - 32) * 5 / 9 }
+func celsiusToKelvin(c float64) float64     { return c + 273.15 }
+
+// convertFromFahrenheit converts a Fahrenheit reading into the requested unit system.
+func convertFromFahrenheit(tempF float64, units Units) float64 {
+	c := fahrenheitToCelsius(tempF)
+	switch units {
+	case UnitsMetric:
+		return c
+	case UnitsStandard:
+		return celsiusToKelvin(c)
+	default:
+		return tempF
+	}
+}
+
+// characterizeTemperature classifies a Celsius temperature as hot, cold, or
+// moderate, using thresholds equivalent to the original Fahrenheit-only
+// behavior (tempF >= 80 is "hot", tempF <= 40 is "cold").
+func characterizeTemperature(tempC float64) string {
+	switch {
+	case tempC >= fahrenheitToCelsius(80):
+		return "hot"
+	case tempC <= fahrenheitToCelsius(40):
+		return "cold"
+	default:
+		return "moderate"
+	}
+}
+
+// windChillF computes the NWS wind chill index (°F) for a temperature (°F)
+// and wind speed (mph). Only valid when tempF < 50 && windMph > 3.
+func windChillF(tempF, windMph float64) float64 {
+	v16 := math.Pow(windMph, 0.16)
+	return 35.74 + 0.6215*tempF - 35.75*v16 + 0.4275*tempF*v16
+}
+
+// heatIndexF computes the NWS Rothfusz regression heat index (°F) for a
+// temperature (°F) and relative humidity (%). Only valid when tempF >= 80.
+func heatIndexF(tempF, humidity float64) float64 {
+	t, r := tempF, humidity
+	return -42.379 + 2.04901523*t + 10.14333127*r -
+		0.22475541*t*r - 0.00683783*t*t - 0.05481717*r*r +
+		0.00122874*t*t*r + 0.00085282*t*r*r - 0.00000199*t*t*r*r
+}
+
+// feelsLikeF returns the apparent temperature (°F) for the given conditions,
+// applying wind chill or heat index when the inputs call for it, and falling
+// back to the raw temperature otherwise.
+func feelsLikeF(tempF, windMph, humidity float64) float64 {
+	switch {
+	case tempF < 50 && windMph > 3:
+		return windChillF(tempF, windMph)
+	case tempF >= 80:
+		return heatIndexF(tempF, humidity)
+	default:
+		return tempF
+	}
+}